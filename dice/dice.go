@@ -0,0 +1,27 @@
+// Package dice provides a tiny stand-in for a "real" remote call, like an API
+// request, so the examples in main.go have something to call concurrently.
+package dice
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// ErrSnakeEyes is returned by RollWithError to simulate a roll that failed,
+// e.g. a flaky downstream API call rather than an actual invalid die value.
+var ErrSnakeEyes = errors.New("dice: roll failed")
+
+// Roll simulates rolling a six sided die, returning a value between 1 and 6.
+func Roll() int {
+	return rand.Intn(6) + 1
+}
+
+// RollWithError is a fallible variant of Roll. It simulates a dice rolling
+// service that occasionally fails instead of always succeeding, so callers
+// can practice fanning in a mix of successes and failures.
+func RollWithError() (int, error) {
+	if rand.Intn(10) == 0 {
+		return 0, ErrSnakeEyes
+	}
+	return Roll(), nil
+}
@@ -0,0 +1,69 @@
+package dice
+
+import "time"
+
+// Limiter is a simple token-bucket rate limiter. It caps the number of
+// operations allowed per second across every goroutine that shares it, while
+// still allowing short bursts up to the configured burst size.
+//
+// The zero value is not usable; create one with NewLimiter.
+type Limiter struct {
+	tokens chan struct{}
+	stop   chan struct{}
+}
+
+// NewLimiter creates a Limiter that permits ratePerSecond operations per
+// second, on average, with bursts of up to burst operations at a time.
+//
+// Internally this is a buffered channel of size burst, pre-filled with
+// tokens. A background goroutine refills one token every 1/ratePerSecond,
+// capping at burst tokens so bursts can't accumulate without limit.
+func NewLimiter(ratePerSecond int, burst int) *Limiter {
+	l := &Limiter{
+		tokens: make(chan struct{}, burst),
+		stop:   make(chan struct{}),
+	}
+
+	// Start full, so the first `burst` callers don't have to wait at all.
+	for i := 0; i < burst; i++ {
+		l.tokens <- struct{}{}
+	}
+
+	interval := time.Second / time.Duration(ratePerSecond)
+	go l.refill(interval)
+
+	return l
+}
+
+func (l *Limiter) refill(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			select {
+			case l.tokens <- struct{}{}:
+				// token banked
+			default:
+				// bucket is already full, drop this tick
+			}
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+// Wait blocks until a token is available, then consumes it. Callers should
+// call Wait immediately before performing the rate-limited operation. It is
+// safe to call Wait concurrently from any number of goroutines.
+func (l *Limiter) Wait() {
+	<-l.tokens
+}
+
+// Stop shuts down the background refill goroutine. Once stopped, a Limiter
+// will eventually run out of tokens and every future Wait call will block
+// forever, so only call Stop once the Limiter is no longer needed.
+func (l *Limiter) Stop() {
+	close(l.stop)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tmyers273/go-training/dice"
+)
+
+// benchRolls mirrors NumberOfRolls, kept separate so these benchmarks don't
+// have to rely on main.go's constant matching whatever b.N happens to be.
+const benchRolls = 100
+
+// rollWithCost simulates dice.Roll taking `cost` to run, so we can compare
+// the naive loop, per-roll-channel, and sharded-accumulator versions across
+// a range of roll costs, not just whatever dice.Roll happens to cost today.
+func rollWithCost(cost time.Duration) int {
+	if cost > 0 {
+		time.Sleep(cost)
+	}
+	return dice.Roll()
+}
+
+func naiveLoopSum(cost time.Duration) int {
+	sum := 0
+	for i := 0; i < benchRolls; i++ {
+		sum += rollWithCost(cost)
+	}
+	return sum
+}
+
+func perRollChannelSum(cost time.Duration) int {
+	ch := make(chan int, benchRolls)
+	var wg sync.WaitGroup
+	wg.Add(benchRolls)
+	for i := 0; i < benchRolls; i++ {
+		go func() {
+			defer wg.Done()
+			ch <- rollWithCost(cost)
+		}()
+	}
+	wg.Wait()
+	close(ch)
+
+	sum := 0
+	for roll := range ch {
+		sum += roll
+	}
+	return sum
+}
+
+func shardedAccumulatorSum(cost time.Duration, workers int) int {
+	jobs := make(chan struct{}, benchRolls)
+	for i := 0; i < benchRolls; i++ {
+		jobs <- struct{}{}
+	}
+	close(jobs)
+
+	results := make(chan int, workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			local := 0
+			for range jobs {
+				local += rollWithCost(cost)
+			}
+			results <- local
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	sum := 0
+	for partial := range results {
+		sum += partial
+	}
+	return sum
+}
+
+// benchCosts simulates a range of dice.Roll costs, from free to roughly
+// "real API call" territory, to make the tradeoff in
+// sumRollsUsingShardedAccumulators empirical rather than assumed.
+var benchCosts = []struct {
+	name string
+	cost time.Duration
+}{
+	{"0us", 0},
+	{"10us", 10 * time.Microsecond},
+	{"1ms", 1 * time.Millisecond},
+}
+
+func BenchmarkNaiveLoop(b *testing.B) {
+	for _, c := range benchCosts {
+		c := c
+		b.Run(c.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				naiveLoopSum(c.cost)
+			}
+		})
+	}
+}
+
+func BenchmarkPerRollChannel(b *testing.B) {
+	for _, c := range benchCosts {
+		c := c
+		b.Run(c.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				perRollChannelSum(c.cost)
+			}
+		})
+	}
+}
+
+func BenchmarkShardedAccumulator(b *testing.B) {
+	for _, c := range benchCosts {
+		c := c
+		b.Run(c.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				shardedAccumulatorSum(c.cost, 10)
+			}
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/gammazero/workerpool"
 	"github.com/tmyers273/go-training/dice"
+	"github.com/tmyers273/go-training/pipeline"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -22,6 +25,20 @@ func main() {
 	sumRollsUsingUnbufferedChannel()
 
 	sumRollsUsingConcurrencyLimit()
+	sumRollsWithRateLimit()
+	sumRollsInBatches(30, 5*time.Second)
+	sumRollsCollectingErrors()
+	sumRollsUsingPipeline()
+	sumRollsUsingShardedAccumulators(10)
+}
+
+// Result carries the outcome of a single roll job through a fan-in channel.
+// JobID identifies which of the NumberOfRolls jobs this result belongs to,
+// Value holds the roll on success, and Err holds the failure on failure.
+type Result struct {
+	JobID int
+	Value int
+	Err   error
 }
 
 func rollUsingLoop() {
@@ -186,4 +203,236 @@ func sumRollsUsingConcurrencyLimit() {
 	}
 
 	fmt.Printf("Took %s to sum %d dice rolls using an unbuffered channel and a concurrency limit of %d. Sum is %d\n", time.Since(start), NumberOfRolls, concurrencyLimit, sum)
+}
+
+func sumRollsWithRateLimit() {
+	// The concurrency limit above caps how many rolls can be _in flight_ at once, but a lot of
+	// real APIs instead (or additionally) quota you by a rate: "20 requests/second", usually with
+	// a small burst allowance thrown in. Think of it as a dice rolling service that hands you a
+	// few free rolls up front, then makes you wait for a new one to become available every so
+	// often.
+
+	// That's what dice.Limiter gives us: a little token-bucket that hands out up to `burst`
+	// tokens right away, then refills at `ratePerSecond` tokens/sec after that. Every goroutine
+	// just calls limiter.Wait() before rolling, and it's perfectly safe to share one Limiter
+	// across all of them.
+
+	// Nothing stops us from using this _alongside_ the concurrency-limited workerpool from above,
+	// so let's do both at once: no more than `concurrencyLimit` rolls in flight, AND no more than
+	// `ratePerSecond` rolls started per second.
+
+	start := time.Now()
+	concurrencyLimit := 10
+	wp := workerpool.New(concurrencyLimit)
+
+	ratePerSecond := 20
+	burst := 5
+	limiter := dice.NewLimiter(ratePerSecond, burst)
+	defer limiter.Stop()
+
+	ch := make(chan int)
+	for i := 0; i < NumberOfRolls; i++ {
+		wp.Submit(func() {
+			// Wait for a rate limit token inside the task, right before rolling, not before
+			// Submit. Submit never blocks, so the loop above queues every roll at once, and
+			// each worker then spends a token at the moment its roll actually starts.
+			limiter.Wait()
+			ch <- dice.Roll()
+		})
+	}
+
+	go func() {
+		wp.StopWait()
+		close(ch)
+	}()
+
+	sum := 0
+	for roll := range ch {
+		sum += roll
+	}
+
+	fmt.Printf("Took %s to sum %d dice rolls using a concurrency limit of %d and a rate limit of %d/s (burst %d). Sum is %d\n", time.Since(start), NumberOfRolls, concurrencyLimit, ratePerSecond, burst, sum)
+}
+
+func sumRollsInBatches(batchSize int, pause time.Duration) {
+	// Not every quota-based API paces you with a steady rate like the one above. Some instead
+	// give you a fixed-size window that resets every N calls: "30 requests, then you must wait 5s
+	// before the next 30". Let's build that one too.
+
+	// Question: if we've got 100 rolls, a batch size of 30, and a 5s pause between batches, how
+	// many pauses do we expect?
+
+	// Answer: 3. Batches of 30, 30, 30, then a final batch of 10 - and no pause after that last
+	// one, since there's nothing left to wait for.
+
+	// The tricky part is making sure a batch has truly *finished* before we sleep, not just that
+	// we've *submitted* all of its rolls. Since the workerpool limits concurrency, some rolls in a
+	// batch can still be running even after every Submit call has returned. So each batch gets
+	// its own WaitGroup, sized to the batch, and we block on that before pausing.
+
+	wp := workerpool.New(10)
+	ch := make(chan int, NumberOfRolls)
+	var completed int64
+
+	start := time.Now()
+	for batchStart := 0; batchStart < NumberOfRolls; batchStart += batchSize {
+		n := batchSize
+		if batchStart+n > NumberOfRolls {
+			n = NumberOfRolls - batchStart
+		}
+
+		var batchWg sync.WaitGroup
+		batchWg.Add(n)
+		for i := 0; i < n; i++ {
+			wp.Submit(func() {
+				ch <- dice.Roll()
+				atomic.AddInt64(&completed, 1)
+				batchWg.Done()
+			})
+		}
+
+		// Gate: don't move on to the next batch (or the trailing pause) until every roll
+		// submitted in this one has actually finished.
+		batchWg.Wait()
+
+		// Don't pause after the very last batch - there's nothing left to pace.
+		if batchStart+n < NumberOfRolls {
+			time.Sleep(pause)
+		}
+	}
+
+	wp.StopWait()
+	close(ch)
+
+	sum := 0
+	for roll := range ch {
+		sum += roll
+	}
+
+	fmt.Printf("Took %s to sum %d dice rolls in batches of %d with a %s pause between batches (%d rolls completed). Sum is %d\n", time.Since(start), NumberOfRolls, batchSize, pause, completed, sum)
+}
+
+func sumRollsCollectingErrors() {
+	// Every example up to this point has assumed dice.Roll always succeeds. Real calls don't -
+	// and that breaks a trick a lot of people reach for without thinking about it: ranging over a
+	// results channel NumberOfRolls times. If even one of those rolls fails and never sends a
+	// value, that last receive blocks forever and the whole program deadlocks.
+
+	// dice.RollWithError gives us a roll that occasionally fails, so we can build this properly.
+	// Every job - success or failure - sends exactly one Result down the channel, so we never
+	// have to guess how many values are actually coming. A dedicated goroutine then closes the
+	// results channel once a WaitGroup confirms every job is done, which is what lets
+	// `for range results` terminate cleanly no matter how many rolls actually succeeded.
+
+	wp := workerpool.New(10)
+	results := make(chan Result)
+	var wg sync.WaitGroup
+	wg.Add(NumberOfRolls)
+
+	start := time.Now()
+	for i := 0; i < NumberOfRolls; i++ {
+		jobID := i
+		wp.Submit(func() {
+			defer wg.Done()
+			value, err := dice.RollWithError()
+			results <- Result{JobID: jobID, Value: value, Err: err}
+		})
+	}
+
+	// This goroutine's only job is to close the channel once every job has sent its Result,
+	// whatever that Result was. Closing it anywhere else risks closing before a slow job sends.
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	sum := 0
+	failures := 0
+	for result := range results {
+		if result.Err != nil {
+			failures++
+			continue
+		}
+		sum += result.Value
+	}
+
+	fmt.Printf("Took %s to sum %d dice rolls collecting errors. %d rolls failed. Sum of successful rolls is %d\n", time.Since(start), NumberOfRolls, failures, sum)
+}
+
+func sumRollsUsingPipeline() {
+	// Every example above is a single function wiring up its own channels and goroutines start to
+	// finish. Let's try a different shape: instead of one big function, we break "roll some dice,
+	// transform the results, add them up" into small, reusable stages from the pipeline package
+	// and wire them together here:
+	//
+	//   Generator -> Roll stage (10 workers) -> Doubling stage (5 workers) -> Sink
+	//
+	// Each stage owns its own output channel and closes it for us once its input dries up, so we
+	// never have to manually close anything ourselves. We're also threading a context.Context
+	// through every stage - cancel it (say, on a timeout, or because we stopped caring about the
+	// result) and the whole pipeline tears itself down instead of leaking goroutines.
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	jobs := pipeline.Generator(ctx, NumberOfRolls)
+
+	rolls := pipeline.Stage(ctx, jobs, 10, func(job pipeline.Job) pipeline.Result {
+		return pipeline.Result{JobID: job.ID, Value: dice.Roll()}
+	})
+
+	doubled := pipeline.Stage(ctx, rolls, 5, func(result pipeline.Result) pipeline.Result {
+		return pipeline.Result{JobID: result.JobID, Value: result.Value * 2}
+	})
+
+	summary := pipeline.Sink(ctx, doubled)
+
+	fmt.Printf("Took %s to sum %d doubled dice rolls using a pipeline. Processed %d results, sum is %d\n", time.Since(start), NumberOfRolls, summary.Count, summary.Sum)
+}
+
+func sumRollsUsingShardedAccumulators(workers int) {
+	// Here's a footgun worth calling out: every concurrent version above sends one value per roll
+	// over a channel, and that's a real cost. When dice.Roll is slow - a genuine API call - that
+	// cost is noise. But dice.Roll here is about as cheap as it gets, and a channel send/receive
+	// on every single roll can easily cost more than the roll itself. Take a look at
+	// dice_bench_test.go; for cheap rolls, "concurrent" can lose to the plain sequential loop.
+
+	// So let's stop communicating on every roll. Spin up exactly `workers` goroutines, each
+	// pulling jobs off a shared channel and keeping its own *local* running sum. A worker only
+	// sends once, its final partial sum, once it runs out of jobs. The main goroutine then just
+	// adds up `workers` partial sums instead of NumberOfRolls individual ones.
+
+	jobs := make(chan struct{}, NumberOfRolls)
+	for i := 0; i < NumberOfRolls; i++ {
+		jobs <- struct{}{}
+	}
+	close(jobs)
+
+	results := make(chan int, workers)
+	start := time.Now()
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			local := 0
+			for range jobs {
+				local += dice.Roll()
+			}
+			results <- local
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	sum := 0
+	for partial := range results {
+		sum += partial
+	}
+
+	fmt.Printf("Took %s to sum %d dice rolls using %d sharded accumulators. Sum is %d\n", time.Since(start), NumberOfRolls, workers, sum)
 }
\ No newline at end of file
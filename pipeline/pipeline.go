@@ -0,0 +1,112 @@
+// Package pipeline models the dice-rolling examples in main.go as a chain of
+// composable stages, instead of one big function. A Generator produces Jobs,
+// one or more Stages transform them, and a Sink consumes the final results.
+//
+// Every stage owns its output channel: it closes that channel once its input
+// channel is closed and every in-flight item has been processed, using an
+// internal sync.WaitGroup. That means callers can chain stages together
+// without ever manually closing a channel themselves, and a context.Context
+// can be threaded through to tear the whole pipeline down early.
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// Job is the unit of work produced by a Generator and consumed by the first
+// Stage in a pipeline.
+type Job struct {
+	ID int
+}
+
+// Result is the output of a Stage. JobID ties it back to the Job it
+// originated from, even after passing through several stages.
+type Result struct {
+	JobID int
+	Value int
+}
+
+// Summary is what a Sink produces after consuming every Result in a pipeline.
+type Summary struct {
+	Count int
+	Sum   int
+}
+
+// Generator produces n Jobs, with IDs 0..n-1, on the returned channel. The
+// channel is closed once all n Jobs have been sent, or immediately if ctx is
+// cancelled first.
+func Generator(ctx context.Context, n int) <-chan Job {
+	out := make(chan Job)
+
+	go func() {
+		defer close(out)
+		for i := 0; i < n; i++ {
+			select {
+			case out <- Job{ID: i}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// Stage runs fn over every In received from in, using workers goroutines,
+// and sends each Out on the returned channel. The returned channel is
+// closed once in is closed and every in-flight In has been processed, or
+// immediately if ctx is cancelled first. This is what lets stages chain: the
+// first Stage in a pipeline is typically Job -> Result, and later stages
+// chain Result -> Result, e.g. Roll -> Double -> Sum.
+func Stage[In, Out any](ctx context.Context, in <-chan In, workers int, fn func(In) Out) <-chan Out {
+	out := make(chan Out)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case item, ok := <-in:
+					if !ok {
+						return
+					}
+					select {
+					case out <- fn(item):
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// Sink consumes every Result from in and returns a Summary once in is
+// closed, or immediately if ctx is cancelled first.
+func Sink(ctx context.Context, in <-chan Result) Summary {
+	var summary Summary
+	for {
+		select {
+		case result, ok := <-in:
+			if !ok {
+				return summary
+			}
+			summary.Count++
+			summary.Sum += result.Value
+		case <-ctx.Done():
+			return summary
+		}
+	}
+}